@@ -0,0 +1,349 @@
+package structflag
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LoadFile populates v, a pointer to a struct, from the config file at path. The format is
+// chosen from the file extension: ".json" for JSON, ".yaml"/".yml" for YAML and ".ini" for
+// INI. Field names are resolved exactly as StructToFlags resolves flag names (flag tag, then
+// json tag, then the package-level NameMapper; see SetNameMapper), so the same tags drive
+// both flags and file-backed config. Nested struct fields map to a nested JSON/YAML object or
+// an INI section, named the same way StructToFlags names a nested flag's prefix segment;
+// anonymous/embedded fields without their own flag or json tag are flattened into their
+// parent's level, same as for flags.
+//
+// StructToFlags sets every field it registers to its env value or tag default (the zero value
+// when there is neither), replacing any value LoadFile put there; only a field with a
+// registered Parser and no env value or tag default keeps it. Calling LoadFile before
+// StructToFlags therefore does not layer the file beneath env and flags. Calling it after
+// StructToFlags and before flag.Parse lets file values replace env values and tag defaults,
+// while flags given on the command line still replace file values.
+func LoadFile(path string, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr {
+		return fmt.Errorf("structflag: LoadFile requires a pointer to a struct, got %T", v)
+	}
+	value = value.Elem()
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("structflag: LoadFile requires a pointer to a struct, got %T", v)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fileData, err := decodeFile(path, data)
+	if err != nil {
+		return err
+	}
+	return populateValue(value, fileData)
+}
+
+// WriteFile writes v, a struct or a pointer to one, to path. The format is chosen from the
+// file extension the same way LoadFile chooses it, and fields are named the same way.
+func WriteFile(path string, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("structflag: WriteFile requires a struct or a pointer to one, got %T", v)
+	}
+	data, err := encodeFile(path, structToMap(value))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func decodeFile(path string, data []byte) (map[string]interface{}, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		fileData := map[string]interface{}{}
+		if err := json.Unmarshal(data, &fileData); err != nil {
+			return nil, err
+		}
+		return fileData, nil
+	case ".yaml", ".yml":
+		fileData := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &fileData); err != nil {
+			return nil, err
+		}
+		return normalizeYAML(fileData), nil
+	case ".ini":
+		return parseINI(data)
+	default:
+		return nil, fmt.Errorf("structflag: unsupported config file extension %q", ext)
+	}
+}
+
+func encodeFile(path string, fileData map[string]interface{}) ([]byte, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return json.MarshalIndent(fileData, "", "  ")
+	case ".yaml", ".yml":
+		return yaml.Marshal(fileData)
+	case ".ini":
+		return writeINI(fileData), nil
+	default:
+		return nil, fmt.Errorf("structflag: unsupported config file extension %q", ext)
+	}
+}
+
+// normalizeYAML converts the map[interface{}]interface{} nodes yaml.v2 produces for nested
+// mappings into map[string]interface{}, so populateValue only ever has to deal with one map
+// type regardless of source format.
+func normalizeYAML(v interface{}) map[string]interface{} {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		for k, val := range m {
+			if nested, ok := toStringMap(val); ok {
+				m[k] = normalizeYAML(nested)
+			}
+		}
+		return m
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(m))
+		for k, val := range m {
+			key := fmt.Sprintf("%v", k)
+			if nested, ok := toStringMap(val); ok {
+				result[key] = normalizeYAML(nested)
+			} else {
+				result[key] = val
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func toStringMap(v interface{}) (interface{}, bool) {
+	switch v.(type) {
+	case map[string]interface{}, map[interface{}]interface{}:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+// populateValue walks value's fields, resolving each one's name the same way registerFlags
+// does, and sets it from the matching entry in data. Missing entries are left untouched so
+// callers may seed zero values themselves before calling LoadFile.
+func populateValue(value reflect.Value, data map[string]interface{}) error {
+	typ := value.Type()
+	for i := 0; i < value.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _, _, hasTag := resolveFieldName(field, nil)
+		if name == "-" {
+			continue
+		}
+		fieldValue := value.Field(i)
+		if parser, ok := parserRegistry[fieldValue.Type()]; ok {
+			raw, exists := data[name]
+			if !exists {
+				continue
+			}
+			parsed, err := parser.Parse(fmt.Sprintf("%v", raw))
+			if err != nil {
+				return fmt.Errorf("structflag: field %s: %w", field.Name, err)
+			}
+			fieldValue.Set(reflect.ValueOf(parsed))
+			continue
+		}
+		if isNestedStruct(fieldValue) && fieldValue.Type() != durationType {
+			nested := data
+			if !(field.Anonymous && !hasTag) {
+				sub, ok := data[name].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				nested = sub
+			}
+			if err := populateValue(fieldValue, nested); err != nil {
+				return err
+			}
+			continue
+		}
+		raw, exists := data[name]
+		if !exists {
+			continue
+		}
+		if err := setScalarField(fieldValue, raw); err != nil {
+			return fmt.Errorf("structflag: field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func setScalarField(fieldValue reflect.Value, raw interface{}) error {
+	s := fmt.Sprintf("%v", raw)
+	switch fieldValue.Kind() {
+	case reflect.Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(v)
+	case reflect.Int64:
+		if fieldValue.Type() == durationType {
+			v, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			fieldValue.SetInt(int64(v))
+		} else {
+			v, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return err
+			}
+			fieldValue.SetInt(v)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetFloat(v)
+	case reflect.String:
+		fieldValue.SetString(s)
+	default:
+		return fmt.Errorf("unsupported field type %s", fieldValue.Type())
+	}
+	return nil
+}
+
+// structToMap is the inverse of populateValue: it renders value's fields into a generic,
+// possibly nested map using the same name resolution, for WriteFile to hand to an encoder.
+func structToMap(value reflect.Value) map[string]interface{} {
+	result := map[string]interface{}{}
+	typ := value.Type()
+	for i := 0; i < value.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _, _, hasTag := resolveFieldName(field, nil)
+		if name == "-" {
+			continue
+		}
+		fieldValue := value.Field(i)
+		if parser, ok := parserRegistry[fieldValue.Type()]; ok {
+			result[name] = parser.String(fieldValue.Interface())
+			continue
+		}
+		if isNestedStruct(fieldValue) && fieldValue.Type() != durationType {
+			nested := structToMap(fieldValue)
+			if field.Anonymous && !hasTag {
+				for k, v := range nested {
+					result[k] = v
+				}
+			} else {
+				result[name] = nested
+			}
+			continue
+		}
+		if fieldValue.Type() == durationType {
+			result[name] = fmt.Sprintf("%v", fieldValue.Interface())
+			continue
+		}
+		result[name] = fieldValue.Interface()
+	}
+	return result
+}
+
+// parseINI reads a minimal INI file: "key = value" pairs, optionally grouped under
+// "[section]" headers; a dotted section name ("db.replica") nests a section inside another,
+// matching how a nested struct's flag names join with a dash.
+func parseINI(data []byte) (map[string]interface{}, error) {
+	root := map[string]interface{}{}
+	current := root
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = root
+			for _, part := range strings.Split(strings.TrimSpace(line[1:len(line)-1]), ".") {
+				next, ok := current[part].(map[string]interface{})
+				if !ok {
+					next = map[string]interface{}{}
+					current[part] = next
+				}
+				current = next
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("structflag: invalid ini line %q", line)
+		}
+		current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// writeINI serializes fileData back into the minimal INI form parseINI reads: top-level
+// scalars first, followed by one "[section]" block per nested map. A struct nested two or
+// more levels deep produces a dotted section name ("[a.b]"), matching how parseINI joins
+// dotted section headers back into nested maps.
+func writeINI(fileData map[string]interface{}) []byte {
+	var buf bytes.Buffer
+	writeINIScalars(&buf, fileData)
+	writeINISections(&buf, "", fileData)
+	return buf.Bytes()
+}
+
+// writeINIScalars writes every non-map entry of fileData as a "key = value" line.
+func writeINIScalars(buf *bytes.Buffer, fileData map[string]interface{}) {
+	for key, value := range fileData {
+		if _, ok := value.(map[string]interface{}); ok {
+			continue
+		}
+		fmt.Fprintf(buf, "%s = %v\n", key, value)
+	}
+}
+
+// writeINISections recurses into fileData's nested maps, emitting a "[prefix.key]" header
+// followed by that section's own scalars before descending further, so a struct nested
+// arbitrarily deep round-trips through parseINI.
+func writeINISections(buf *bytes.Buffer, prefix string, fileData map[string]interface{}) {
+	for key, value := range fileData {
+		section, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		fmt.Fprintf(buf, "[%s]\n", name)
+		writeINIScalars(buf, section)
+		writeINISections(buf, name, section)
+	}
+}
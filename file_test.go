@@ -0,0 +1,108 @@
+package structflag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type FileDBConfig struct {
+	Host string `flag:"host"`
+	Port int    `flag:"port"`
+}
+
+type FileTestStruct struct {
+	Name    string        `flag:"name"`
+	Timeout time.Duration `flag:"timeout"`
+	DB      FileDBConfig  `flag:"db"`
+	Tags    []string      `flag:"tags"`
+	Ignore  string        `flag:"-"`
+}
+
+func TestLoadFileJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{"name":"svc","timeout":"5s","db":{"host":"db.internal","port":5433},"tags":"a,b"}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var s FileTestStruct
+	if err := LoadFile(path, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "svc" || s.Timeout != 5*time.Second || s.DB.Host != "db.internal" || s.DB.Port != 5433 {
+		t.Error("Unexpected struct after LoadFile JSON:", s)
+	}
+}
+
+func TestLoadFileYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "name: svc\ntimeout: 5s\ndb:\n  host: db.internal\n  port: 5433\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var s FileTestStruct
+	if err := LoadFile(path, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "svc" || s.Timeout != 5*time.Second || s.DB.Host != "db.internal" || s.DB.Port != 5433 {
+		t.Error("Unexpected struct after LoadFile YAML:", s)
+	}
+}
+
+func TestLoadFileINI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	content := "name = svc\ntimeout = 5s\n\n[db]\nhost = db.internal\nport = 5433\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var s FileTestStruct
+	if err := LoadFile(path, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "svc" || s.Timeout != 5*time.Second || s.DB.Host != "db.internal" || s.DB.Port != 5433 {
+		t.Error("Unexpected struct after LoadFile INI:", s)
+	}
+}
+
+type FileInnerConfig struct {
+	Leaf string `flag:"leaf"`
+}
+
+type FileMidConfig struct {
+	Inner FileInnerConfig `flag:"inner"`
+}
+
+type FileOuterConfig struct {
+	Mid FileMidConfig `flag:"mid"`
+}
+
+func TestWriteFileRoundTripININestedTwoLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	original := FileOuterConfig{Mid: FileMidConfig{Inner: FileInnerConfig{Leaf: "value"}}}
+	if err := WriteFile(path, &original); err != nil {
+		t.Fatal(err)
+	}
+	var loaded FileOuterConfig
+	if err := LoadFile(path, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded != original {
+		t.Error("Round trip through INI changed nested struct:", loaded)
+	}
+}
+
+func TestWriteFileRoundTripINI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	original := FileTestStruct{Name: "svc", Timeout: 5 * time.Second, DB: FileDBConfig{Host: "db.internal", Port: 5433}, Tags: []string{"a", "b"}}
+	if err := WriteFile(path, &original); err != nil {
+		t.Fatal(err)
+	}
+	var loaded FileTestStruct
+	if err := LoadFile(path, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Name != original.Name || loaded.Timeout != original.Timeout || loaded.DB != original.DB {
+		t.Error("Round trip through INI changed struct:", loaded)
+	}
+}
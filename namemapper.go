@@ -0,0 +1,82 @@
+package structflag
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NameMapper converts a struct field into a flag name segment for use when the field has no
+// explicit flag or json tag. Register one with SetNameMapper to change the convention used
+// across the package, or pass one in Options to override it for a single call.
+type NameMapper func(reflect.StructField) string
+
+// FieldName is the package-level default NameMapper, and reproduces the naming structflag has
+// always used for an untagged field: the field name as is, with underscores replaced by
+// dashes. It exists so that switching to KebabCase, SnakeCase or a custom NameMapper is an
+// opt-in choice rather than a default behavior change.
+func FieldName(field reflect.StructField) string {
+	return strings.Replace(field.Name, "_", "-", -1)
+}
+
+// nameMapper is the package-level default, changed by SetNameMapper.
+var nameMapper NameMapper = FieldName
+
+// SetNameMapper changes the default NameMapper used by StructToFlags, StructToArgs and their
+// WithOptions variants for any field without its own flag or json tag.
+func SetNameMapper(mapper NameMapper) {
+	nameMapper = mapper
+}
+
+// KebabCase lower-cases field.Name and inserts a dash at each case transition and in place of
+// any underscore, so "MyField" and "My_Field" both become "my-field". It is opt-in: pass it to
+// SetNameMapper or Options to use it instead of the default FieldName mapper.
+func KebabCase(field reflect.StructField) string {
+	return splitWords(field.Name, "-")
+}
+
+// SnakeCase inserts an underscore at each case transition and in place of any dash, so
+// "MyField" becomes "my_field".
+func SnakeCase(field reflect.StructField) string {
+	return splitWords(field.Name, "_")
+}
+
+// SnackCase is an alias for SnakeCase, named after go-ini's NameMapper constant of the same
+// name.
+var SnackCase NameMapper = SnakeCase
+
+// Options customizes a single StructToFlagsWithOptions or StructToArgsWithOptions call.
+type Options struct {
+	// NameMapper overrides the package-level NameMapper (see SetNameMapper) for this call
+	// only. A nil NameMapper falls back to the package-level one.
+	NameMapper NameMapper
+}
+
+func (o Options) mapper() NameMapper {
+	if o.NameMapper != nil {
+		return o.NameMapper
+	}
+	return nameMapper
+}
+
+// splitWords lower-cases name and joins its words with sep, treating existing underscores
+// and dashes as word boundaries in addition to case transitions.
+func splitWords(name, sep string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '_' || r == '-' {
+			if b.Len() > 0 {
+				b.WriteString(sep)
+			}
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) && runes[i-1] != '_' && runes[i-1] != '-' &&
+			(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteString(sep)
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
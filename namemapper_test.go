@@ -0,0 +1,40 @@
+package structflag
+
+import (
+	"testing"
+)
+
+type NameMapperTestStruct struct {
+	UserID   string
+	APIToken string
+}
+
+func TestFieldNameDefault(t *testing.T) {
+	args := StructToArgs("", &NameMapperTestStruct{UserID: "u1", APIToken: "tok"})
+	if len(args) != 2 || args[0] != "-UserID=\"u1\"" || args[1] != "-APIToken=\"tok\"" {
+		t.Error("Unexpected default (FieldName) args:", args)
+	}
+}
+
+func TestStructToArgsWithOptionsKebabCase(t *testing.T) {
+	args := StructToArgsWithOptions("", &NameMapperTestStruct{UserID: "u1", APIToken: "tok"}, Options{NameMapper: KebabCase})
+	if len(args) != 2 || args[0] != "-user-id=\"u1\"" || args[1] != "-api-token=\"tok\"" {
+		t.Error("Unexpected KebabCase args:", args)
+	}
+}
+
+func TestStructToArgsWithOptionsSnakeCase(t *testing.T) {
+	args := StructToArgsWithOptions("", &NameMapperTestStruct{UserID: "u1", APIToken: "tok"}, Options{NameMapper: SnakeCase})
+	if len(args) != 2 || args[0] != "-user_id=\"u1\"" || args[1] != "-api_token=\"tok\"" {
+		t.Error("Unexpected SnakeCase args:", args)
+	}
+}
+
+func TestSetNameMapper(t *testing.T) {
+	SetNameMapper(SnakeCase)
+	defer SetNameMapper(FieldName)
+	args := StructToArgs("", &NameMapperTestStruct{UserID: "u1", APIToken: "tok"})
+	if len(args) != 2 || args[0] != "-user_id=\"u1\"" || args[1] != "-api_token=\"tok\"" {
+		t.Error("Unexpected args after SetNameMapper:", args)
+	}
+}
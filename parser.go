@@ -0,0 +1,128 @@
+package structflag
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Parser lets a type outside the built-in bool/int/string switch be registered with
+// StructToFlags and StructToArgs. Parse converts a flag's string value into the field's
+// value; String renders a value of that type back into its flag string form.
+type Parser interface {
+	Parse(string) (interface{}, error)
+	String(interface{}) string
+}
+
+var parserRegistry = map[reflect.Type]Parser{}
+
+// RegisterParser registers parser to handle fields of typ, letting StructToFlags and
+// StructToArgs support types that aren't covered by the built-in switch, such as net.IP,
+// url.URL, or a custom enum. Registering a Parser for a type that's already registered
+// replaces the existing one.
+func RegisterParser(typ reflect.Type, parser Parser) {
+	parserRegistry[typ] = parser
+}
+
+// parserValue adapts a Parser and a field's reflect.Value into a flag.Value so registered
+// types can be wired up with flag.Var.
+type parserValue struct {
+	parser Parser
+	value  reflect.Value
+}
+
+func (v *parserValue) Set(s string) error {
+	parsed, err := v.parser.Parse(s)
+	if err != nil {
+		return err
+	}
+	v.value.Set(reflect.ValueOf(parsed))
+	return nil
+}
+
+func (v *parserValue) String() string {
+	if v.parser == nil || !v.value.IsValid() {
+		return ""
+	}
+	return v.parser.String(v.value.Interface())
+}
+
+func init() {
+	RegisterParser(reflect.TypeOf([]string(nil)), stringSliceParser{})
+	RegisterParser(reflect.TypeOf(map[string]string(nil)), stringMapParser{})
+	RegisterParser(reflect.TypeOf(time.Time{}), timeParser{})
+}
+
+// stringSliceParser is the built-in Parser for []string, using a comma-separated string as
+// its flag representation.
+type stringSliceParser struct{}
+
+func (stringSliceParser) Parse(s string) (interface{}, error) {
+	if s == "" {
+		return []string{}, nil
+	}
+	return strings.Split(s, ","), nil
+}
+
+func (stringSliceParser) String(v interface{}) string {
+	return strings.Join(v.([]string), ",")
+}
+
+// stringMapParser is the built-in Parser for map[string]string, using a comma-separated list
+// of "key=value" pairs as its flag representation.
+type stringMapParser struct{}
+
+func (stringMapParser) Parse(s string) (interface{}, error) {
+	result := map[string]string{}
+	if s == "" {
+		return result, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("structflag: invalid map entry %q, expected key=value", pair)
+		}
+		result[kv[0]] = kv[1]
+	}
+	return result, nil
+}
+
+func (stringMapParser) String(v interface{}) string {
+	m := v.(map[string]string)
+	pairs := make([]string, 0, len(m))
+	for k, val := range m {
+		pairs = append(pairs, k+"="+val)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// timeParserFormats are the layouts timeParser tries, in order, when parsing a flag value.
+var timeParserFormats = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+}
+
+// timeParser is the built-in Parser for time.Time, trying each of timeParserFormats in turn
+// and rendering back out using time.RFC3339.
+type timeParser struct{}
+
+func (timeParser) Parse(s string) (interface{}, error) {
+	var lastErr error
+	for _, layout := range timeParserFormats {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+		lastErr = err
+	}
+	return time.Time{}, lastErr
+}
+
+func (timeParser) String(v interface{}) string {
+	return v.(time.Time).Format(time.RFC3339)
+}
@@ -0,0 +1,44 @@
+package structflag
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type ParserTestStruct struct {
+	Tags  []string          `flag:"tags,,solo"`
+	Attrs map[string]string `flag:"attrs"`
+	Start time.Time         `flag:"start,,2020-01-02T15:04:05Z"`
+}
+
+func TestStructToArgsParser(t *testing.T) {
+	start, _ := time.Parse(time.RFC3339, "2020-01-02T15:04:05Z")
+	args := StructToArgs("", &ParserTestStruct{Tags: []string{"a", "b"}, Attrs: map[string]string{"k": "v"}, Start: start})
+	if len(args) != 3 || args[0] != "-tags=\"a,b\"" || args[1] != "-attrs=\"k=v\"" || args[2] != "-start=\"2020-01-02T15:04:05Z\"" {
+		t.Error("Unexpected parser args:", args)
+	}
+}
+
+func TestStructToFlagsParser(t *testing.T) {
+	var parserTestStruct ParserTestStruct
+	StructToFlags("parser", &parserTestStruct)
+	flag.Parse()
+	if !reflect.DeepEqual(parserTestStruct.Tags, []string{"solo"}) {
+		t.Error("Failed to parse default for []string:", parserTestStruct.Tags)
+	}
+	if len(parserTestStruct.Attrs) != 0 {
+		t.Error("Expected empty default map:", parserTestStruct.Attrs)
+	}
+	if parserTestStruct.Start.Format(time.RFC3339) != "2020-01-02T15:04:05Z" {
+		t.Error("Failed to parse default time.Time:", parserTestStruct.Start)
+	}
+}
+
+func TestRegisterParser(t *testing.T) {
+	typ := reflect.TypeOf([]string(nil))
+	if _, ok := parserRegistry[typ]; !ok {
+		t.Error("Expected built-in []string parser to be registered")
+	}
+}
@@ -4,17 +4,60 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
 )
 
-func makeFlagName(prefix, flagName string) string {
-	return prefix + strings.Replace(flagName, "_", "-", -1)
+// autoEnvPrefix holds the prefix set by AutoEnv, and autoEnvEnabled records whether AutoEnv
+// has been called. When enabled, flags without an explicit env tag fall back to an
+// environment variable derived from the flag name.
+var autoEnvPrefix string
+var autoEnvEnabled bool
+
+// AutoEnv enables the implicit environment variable fallback for every flag that does not
+// declare its own env tag. The variable name is built from prefix and the flag name, e.g. a
+// flag named "field-name" with prefix "MYAPP" looks up "MYAPP_FIELD_NAME". Call AutoEnv("") to
+// enable the fallback without a prefix. Once enabled, the fallback stays enabled; a later
+// call only changes the prefix.
+func AutoEnv(prefix string) {
+	autoEnvPrefix = prefix
+	autoEnvEnabled = true
+}
+
+// autoEnvName derives the implicit environment variable name for a flag, e.g. "db-host"
+// becomes "PREFIX_DB_HOST".
+func autoEnvName(flagName string) string {
+	name := strings.ToUpper(strings.Replace(flagName, "-", "_", -1))
+	if autoEnvPrefix == "" {
+		return name
+	}
+	return strings.ToUpper(autoEnvPrefix) + "_" + name
+}
+
+// envDefault resolves the default value for a flag, applying the flag > env > tag default
+// precedence (flag.Parse, called by the caller after StructToFlags, applies the flag part).
+// envVar is the explicit env:"VAR" tag value, if any; flagName is the already-resolved,
+// prefixed flag name used to derive the implicit AutoEnv variable name.
+func envDefault(envVar, flagName, defaultValue string) string {
+	if envVar != "" {
+		if v, ok := os.LookupEnv(envVar); ok {
+			return v
+		}
+		return defaultValue
+	}
+	if autoEnvEnabled {
+		if v, ok := os.LookupEnv(autoEnvName(flagName)); ok {
+			return v
+		}
+	}
+	return defaultValue
 }
 
-func parseFlagTag(field reflect.StructField) (name string, description string, defaultValue string) {
+func parseFlagTag(field reflect.StructField) (name string, description string, defaultValue string, envVar string) {
+	envVar = field.Tag.Get("env")
 	tagValue := field.Tag.Get("flag")
 	if tagValue == "" {
 		return
@@ -32,6 +75,52 @@ func parseFlagTag(field reflect.StructField) (name string, description string, d
 
 var durationType = reflect.TypeOf(time.Duration(0))
 
+// resolveFieldName computes the flag name segment for a single field along with its
+// description, default value and env tag, applying the flag > json > NameMapper precedence
+// shared by StructToArgs and StructToFlags. hasTag reports whether a flag or json tag
+// explicitly named the field, which nested-struct handling uses to decide whether an
+// embedded field should introduce its own name segment. mapper is used only when neither tag
+// names the field; pass nil to use the package-level default set by SetNameMapper.
+func resolveFieldName(field reflect.StructField, mapper NameMapper) (name string, description string, defaultValue string, envVar string, hasTag bool) {
+	flagTagName, flagDescription, flagDefaultValue, flagEnvVar := parseFlagTag(field)
+	description = flagDescription
+	defaultValue = flagDefaultValue
+	envVar = flagEnvVar
+	if flagTagName != "" {
+		name = strings.Replace(flagTagName, "_", "-", -1)
+		hasTag = true
+	} else if jsonName := field.Tag.Get("json"); jsonName != "" {
+		name = strings.Replace(strings.Split(jsonName, ",")[0], "_", "-", -1)
+		hasTag = true
+	} else {
+		if mapper == nil {
+			mapper = nameMapper
+		}
+		name = mapper(field)
+	}
+	return
+}
+
+// joinSegment appends a name segment to prefix, separating the two with a dash. An empty
+// segment leaves prefix unchanged, which is how embedded struct fields without a name
+// override are flattened into their parent's level.
+func joinSegment(prefix, segment string) string {
+	if segment == "" {
+		return prefix
+	}
+	if prefix == "" {
+		return segment
+	}
+	return prefix + "-" + segment
+}
+
+// isNestedStruct reports whether fieldValue should be descended into rather than treated as
+// a leaf flag. time.Duration is a reflect.Struct-free int64 under the hood, so it never
+// matches here and continues to be handled as a duration flag.
+func isNestedStruct(fieldValue reflect.Value) bool {
+	return fieldValue.Kind() == reflect.Struct
+}
+
 // StructToArgs converts a struct into a list of arguments of the form '-field-name=value'.
 // If prefix is not empty, args will take the form '-prefix-field-name=value'. String values
 // are quoted, bool, int and float values are not. Note that not all types supported by
@@ -40,11 +129,31 @@ var durationType = reflect.TypeOf(time.Duration(0))
 // A flag:"name" tag may be applied to the struct fields to set a custom field name. If no
 // matching tag is found, structflag will try to use a "json" tag. Otherwise the field name
 // is used as is. Finally, all underscores in the field name are replaced with '-'.
+//
+// Nested struct fields are descended into recursively, joining each level's name segment with
+// a dash (a "Host" field of a "DB" field becomes "db-host"). Anonymous/embedded struct fields
+// without their own flag or json tag are flattened into their parent's segment instead of
+// introducing one of their own.
+//
+// Fields whose type has a registered Parser (see RegisterParser) are rendered with that
+// Parser's String method and quoted like string values.
+//
+// A field without a flag or json tag is named by the package-level NameMapper (FieldName by
+// default, which preserves the field's name as is; see SetNameMapper). Use
+// StructToArgsWithOptions to override it for a single call. The NameMapper's result is used
+// as is, without the underscore replacement described above.
+//
+// ignoredFields excludes fields from the result, matched against each field's full
+// dash-joined path from the struct root ("db-port" for a Port field nested under DB), so ignoring one
+// field never affects a same-named field elsewhere in the struct.
 func StructToArgs(prefix string, v interface{}, ignoredFields ...string) (args []string) {
+	return StructToArgsWithOptions(prefix, v, Options{}, ignoredFields...)
+}
+
+// StructToArgsWithOptions behaves like StructToArgs but applies opts, most notably
+// opts.NameMapper, for this call only.
+func StructToArgsWithOptions(prefix string, v interface{}, opts Options, ignoredFields ...string) (args []string) {
 	ignored := newStringSet(ignoredFields...)
-	if prefix != "" {
-		prefix = prefix + "-"
-	}
 	value := reflect.ValueOf(v)
 	if value.Kind() == reflect.Ptr {
 		value = value.Elem()
@@ -52,27 +161,41 @@ func StructToArgs(prefix string, v interface{}, ignoredFields ...string) (args [
 	if value.Kind() != reflect.Struct {
 		log.Panicln("Can't call StructToArgs with value:", v, "type:", value.Type())
 	}
+	return appendArgs(make([]string, 0, value.NumField()), prefix, "", value, ignored, opts.mapper())
+}
+
+// appendArgs walks value's fields, tracking both prefix (the external, caller-supplied flag
+// prefix the flag is ultimately registered under) and path (the field's own dash-joined
+// position within the struct, rooted at the value passed to StructToArgs). ignoredFields is matched
+// against path rather than prefix, so ignoring "db-port" only drops that field and leaves any
+// other "port" field elsewhere in the struct untouched.
+func appendArgs(args []string, prefix, path string, value reflect.Value, ignored stringSet, mapper NameMapper) []string {
 	typ := value.Type()
 	fields := value.NumField()
-	args = make([]string, 0, fields)
 	for i := 0; i < fields; i++ {
 		stringValue := ""
 		field := typ.Field(i)
-		flagName := field.Name
-		flagTagName, _, _ := parseFlagTag(field)
-		if flagTagName != "" {
-			flagName = flagTagName
-		} else {
-			jsonName := field.Tag.Get("json")
-			if jsonName != "" {
-				flagName = strings.Split(jsonName, ",")[0]
-			}
-		}
-		flagName = strings.Replace(flagName, "_", "-", -1)
-		if flagName == "-" || ignored.Contains(flagName) {
+		flagName, _, _, _, hasTag := resolveFieldName(field, mapper)
+		fieldPath := joinSegment(path, flagName)
+		if flagName == "-" || ignored.Contains(fieldPath) {
 			continue
 		}
 		fieldValue := value.Field(i)
+		if parser, ok := parserRegistry[fieldValue.Type()]; ok {
+			stringValue = strconv.Quote(parser.String(fieldValue.Interface()))
+			args = append(args, fmt.Sprintf("-%s=%s", joinSegment(prefix, flagName), stringValue))
+			continue
+		}
+		if isNestedStruct(fieldValue) && fieldValue.Type() != durationType {
+			segment := flagName
+			childPath := fieldPath
+			if field.Anonymous && !hasTag {
+				segment = ""
+				childPath = path
+			}
+			args = appendArgs(args, joinSegment(prefix, segment), childPath, fieldValue, ignored, mapper)
+			continue
+		}
 		switch fieldValue.Kind() {
 		case reflect.Bool:
 			fallthrough
@@ -109,9 +232,9 @@ func StructToArgs(prefix string, v interface{}, ignoredFields ...string) (args [
 		default:
 			stringValue = strconv.Quote(fmt.Sprintf("%v", fieldValue.Interface()))
 		}
-		args = append(args, fmt.Sprintf("-%s=%s", makeFlagName(prefix, flagName), stringValue))
+		args = append(args, fmt.Sprintf("-%s=%s", joinSegment(prefix, flagName), stringValue))
 	}
-	return
+	return args
 }
 
 // StructToFlags registers the fields of a struct with the flag package so they may be set
@@ -123,13 +246,42 @@ func StructToArgs(prefix string, v interface{}, ignoredFields ...string) (args [
 // Finally, all underscores in the field name are replaced with '-'.
 // Default values are parsed using normal string conversion methods for the value type.
 //
-// Supported field types: bool, int, int64, uint,uint64, float64, time.Duration, string
-// Calling StructToFlags with a struct containing unsupported fields will panic.
+// An env:"VAR" tag may be applied to a field to seed its default from the environment; if
+// AutoEnv has been called, fields without an explicit env tag fall back to a variable name
+// derived from the flag name. Precedence is flag > env > tag default.
+//
+// Nested struct fields are descended into recursively, joining each level's name segment with
+// a dash (a "Host" field of a "DB" field becomes "-db-host"); a flag tag on the struct field
+// itself overrides that segment's name. Anonymous/embedded struct fields without their own
+// flag or json tag are flattened into their parent's segment instead of introducing one of
+// their own.
+//
+// Fields whose type has a registered Parser (see RegisterParser) are wired up with flag.Var
+// using that Parser, checked before the nested-struct case above, so a registered struct type
+// such as time.Time is treated as a leaf flag rather than descended into. Built-in Parsers are
+// registered for []string (comma-separated), map[string]string ("k=v,k=v") and time.Time
+// (tried against a short list of common layouts).
+//
+// Supported field types: bool, int, int64, uint,uint64, float64, time.Duration, string, nested
+// structs, and any type with a registered Parser. Calling StructToFlags with a struct
+// containing other unsupported fields will panic.
+//
+// A field without a flag or json tag is named by the package-level NameMapper (FieldName by
+// default, which preserves the field's name as is; see SetNameMapper). Use
+// StructToFlagsWithOptions to override it for a single call. The NameMapper's result is used
+// as is, without the underscore replacement described above.
+//
+// ignoredFields excludes fields from registration, matched against each field's full
+// dash-joined path from the struct root ("db-port" for a Port field nested under DB), so ignoring one
+// field never affects a same-named field elsewhere in the struct.
 func StructToFlags(prefix string, v interface{}, ignoredFields ...string) {
+	StructToFlagsWithOptions(prefix, v, Options{}, ignoredFields...)
+}
+
+// StructToFlagsWithOptions behaves like StructToFlags but applies opts, most notably
+// opts.NameMapper, for this call only.
+func StructToFlagsWithOptions(prefix string, v interface{}, opts Options, ignoredFields ...string) {
 	ignored := newStringSet(ignoredFields...)
-	if prefix != "" {
-		prefix = prefix + "-"
-	}
 	value := reflect.ValueOf(v)
 	if value.Kind() != reflect.Ptr {
 		log.Panicln("Can't call StructToFlags with value:", v, "type:", value.Type(), "must be a pointer to a struct.")
@@ -138,57 +290,77 @@ func StructToFlags(prefix string, v interface{}, ignoredFields ...string) {
 	if value.Kind() != reflect.Struct {
 		log.Panicln("Can't call StructToFlags with value:", v, "type:", value.Type(), "must be a pointer to a struct.")
 	}
+	registerFlags(prefix, "", value, ignored, opts.mapper())
+}
 
+// registerFlags walks value's fields, tracking both prefix (the external, caller-supplied flag
+// prefix the flag is ultimately registered under) and path (the field's own dash-joined
+// position within the struct, rooted at the value passed to StructToFlags). ignoredFields is matched
+// against path rather than prefix, so ignoring "db-port" only drops that field and leaves any
+// other "port" field elsewhere in the struct untouched.
+func registerFlags(prefix, path string, value reflect.Value, ignored stringSet, mapper NameMapper) {
 	typ := value.Type()
 	fields := value.NumField()
 	for i := 0; i < fields; i++ {
 		field := typ.Field(i)
-		flagName := field.Name
-		flagTagName, flagDescription, flagDefaultValue := parseFlagTag(field)
-		if flagTagName != "" {
-			flagName = flagTagName
-		} else {
-			jsonName := field.Tag.Get("json")
-			if jsonName != "" {
-				flagName = strings.Split(jsonName, ",")[0]
+		flagName, flagDescription, flagDefaultValue, envVar, hasTag := resolveFieldName(field, mapper)
+		fieldPath := joinSegment(path, flagName)
+		if flagName == "-" || ignored.Contains(fieldPath) {
+			continue
+		}
+		fieldValue := value.Field(i)
+		fullName := joinSegment(prefix, flagName)
+		if parser, ok := parserRegistry[fieldValue.Type()]; ok {
+			flagDefaultValue = envDefault(envVar, fullName, flagDefaultValue)
+			if flagDefaultValue != "" {
+				if def, err := parser.Parse(flagDefaultValue); err == nil {
+					fieldValue.Set(reflect.ValueOf(def))
+				}
 			}
+			flag.Var(&parserValue{parser: parser, value: fieldValue}, fullName, flagDescription)
+			continue
 		}
-		flagName = strings.Replace(flagName, "_", "-", -1)
-		if flagName == "-" || ignored.Contains(flagName) {
+		if isNestedStruct(fieldValue) && fieldValue.Type() != durationType {
+			segment := flagName
+			childPath := fieldPath
+			if field.Anonymous && !hasTag {
+				segment = ""
+				childPath = path
+			}
+			registerFlags(joinSegment(prefix, segment), childPath, fieldValue, ignored, mapper)
 			continue
 		}
-		fieldValue := value.Field(i)
+		flagDefaultValue = envDefault(envVar, fullName, flagDefaultValue)
 		switch fieldValue.Kind() {
 		case reflect.Bool:
 			def, _ := strconv.ParseBool(flagDefaultValue)
-			flag.BoolVar(fieldValue.Addr().Interface().(*bool), makeFlagName(prefix, flagName), def, flagDescription)
+			flag.BoolVar(fieldValue.Addr().Interface().(*bool), fullName, def, flagDescription)
 		case reflect.Int:
 			def, _ := strconv.Atoi(flagDefaultValue)
-			flag.IntVar(fieldValue.Addr().Interface().(*int), makeFlagName(prefix, flagName), def, flagDescription)
+			flag.IntVar(fieldValue.Addr().Interface().(*int), fullName, def, flagDescription)
 		case reflect.Int64:
 			if fieldValue.Type() == durationType {
 				def, _ := time.ParseDuration(flagDefaultValue)
-				flag.DurationVar(fieldValue.Addr().Interface().(*time.Duration), makeFlagName(prefix, flagName), def, flagDescription)
+				flag.DurationVar(fieldValue.Addr().Interface().(*time.Duration), fullName, def, flagDescription)
 			} else {
 				def, _ := strconv.ParseInt(flagDefaultValue, 10, 64)
-				flag.Int64Var(fieldValue.Addr().Interface().(*int64), makeFlagName(prefix, flagName), def, flagDescription)
+				flag.Int64Var(fieldValue.Addr().Interface().(*int64), fullName, def, flagDescription)
 			}
 		case reflect.Uint:
 			def, _ := strconv.ParseUint(flagDefaultValue, 10, 64)
-			flag.UintVar(fieldValue.Addr().Interface().(*uint), makeFlagName(prefix, flagName), uint(def), flagDescription)
+			flag.UintVar(fieldValue.Addr().Interface().(*uint), fullName, uint(def), flagDescription)
 		case reflect.Uint64:
 			def, _ := strconv.ParseUint(flagDefaultValue, 10, 64)
-			flag.Uint64Var(fieldValue.Addr().Interface().(*uint64), makeFlagName(prefix, flagName), def, flagDescription)
+			flag.Uint64Var(fieldValue.Addr().Interface().(*uint64), fullName, def, flagDescription)
 		case reflect.Float64:
 			def, _ := strconv.ParseFloat(flagDefaultValue, 64)
-			flag.Float64Var(fieldValue.Addr().Interface().(*float64), makeFlagName(prefix, flagName), def, flagDescription)
+			flag.Float64Var(fieldValue.Addr().Interface().(*float64), fullName, def, flagDescription)
 		case reflect.String:
-			flag.StringVar(fieldValue.Addr().Interface().(*string), makeFlagName(prefix, flagName), flagDefaultValue, flagDescription)
+			flag.StringVar(fieldValue.Addr().Interface().(*string), fullName, flagDefaultValue, flagDescription)
 		default:
 			log.Panicln("Invalid field type:", field)
 		}
 	}
-	return
 }
 
 type stringSet map[string]struct{}
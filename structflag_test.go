@@ -2,6 +2,7 @@ package structflag
 
 import (
 	"flag"
+	"os"
 	"testing"
 	"time"
 )
@@ -13,6 +14,69 @@ type TestStruct struct {
 	Duration time.Duration `flag:"interval,Some description,5s"`
 }
 
+type EnvTestStruct struct {
+	Explicit string `flag:"explicit,,fallback" env:"STRUCTFLAG_EXPLICIT"`
+	Auto     string `flag:"auto-field,,fallback"`
+}
+
+type DBConfig struct {
+	Host string `flag:"host,,localhost"`
+	Port int    `flag:"port,,5432"`
+}
+
+type Common struct {
+	LogLevel string `flag:"log-level,,info"`
+}
+
+type NestedTestStruct struct {
+	Common
+	DB   DBConfig `flag:"db"`
+	Name string   `flag:"name,,app"`
+}
+
+type CollisionTestStruct struct {
+	Port int      `flag:"port,,80"`
+	DB   DBConfig `flag:"db"`
+}
+
+func TestStructToArgsIgnoredFieldsFullPath(t *testing.T) {
+	v := CollisionTestStruct{Port: 8080, DB: DBConfig{Host: "db.internal", Port: 5433}}
+	args := StructToArgs("", &v, "port")
+	if len(args) != 2 || args[0] != "-db-host=\"db.internal\"" || args[1] != "-db-port=5433" {
+		t.Error("Ignoring top-level \"port\" should not drop nested db-port:", args)
+	}
+}
+
+func TestStructToFlagsIgnoredFieldsFullPath(t *testing.T) {
+	var v CollisionTestStruct
+	StructToFlags("collision", &v, "port")
+	if flag.Lookup("collision-port") != nil {
+		t.Error("Expected top-level port flag to be ignored")
+	}
+	if flag.Lookup("collision-db-port") == nil {
+		t.Error("Ignoring top-level \"port\" should not drop nested db-port flag")
+	}
+}
+
+func TestStructToArgsNested(t *testing.T) {
+	args := StructToArgs("", &NestedTestStruct{Common: Common{LogLevel: "debug"}, DB: DBConfig{Host: "db.internal", Port: 5433}, Name: "svc"})
+	if len(args) != 4 || args[0] != "-log-level=\"debug\"" || args[1] != "-db-host=\"db.internal\"" || args[2] != "-db-port=5433" || args[3] != "-name=\"svc\"" {
+		t.Error("Unexpected nested args:", args)
+	}
+}
+
+func TestStructToFlagsNested(t *testing.T) {
+	var nestedTestStruct NestedTestStruct
+	StructToFlags("nested", &nestedTestStruct)
+	flag.Parse()
+	if nestedTestStruct.LogLevel != "info" {
+		t.Error("Failed to parse embedded field:", nestedTestStruct)
+	}
+	if nestedTestStruct.DB.Host != "localhost" || nestedTestStruct.DB.Port != 5432 {
+		t.Error("Failed to parse nested struct field:", nestedTestStruct)
+	}
+}
+
 func TestStructToArgs(t *testing.T) {
 	args := StructToArgs("", &TestStruct{String: "some \"string\" with spaces", Int: 42, Bool: true, Duration: time.Minute})
 	if len(args) != 4 || args[0] != "-string-with-underscores=\"some \\\"string\\\" with spaces\"" || args[1] != "-number=42" || args[2] != "-yes-no=true" || args[3] != "-interval=1m0s" {
@@ -37,3 +101,21 @@ func TestStructToFlags(t *testing.T) {
 		t.Error("Failed to parse with prefix:", testStruct2)
 	}
 }
+
+func TestStructToFlagsEnv(t *testing.T) {
+	os.Setenv("STRUCTFLAG_EXPLICIT", "from-env")
+	defer os.Unsetenv("STRUCTFLAG_EXPLICIT")
+	AutoEnv("STRUCTFLAG")
+	os.Setenv("STRUCTFLAG_ENV_AUTO_FIELD", "from-auto-env")
+	defer os.Unsetenv("STRUCTFLAG_ENV_AUTO_FIELD")
+
+	var envTestStruct EnvTestStruct
+	StructToFlags("env", &envTestStruct)
+	flag.Parse()
+	if envTestStruct.Explicit != "from-env" {
+		t.Error("Failed to fall back to explicit env var:", envTestStruct)
+	}
+	if envTestStruct.Auto != "from-auto-env" {
+		t.Error("Failed to fall back to auto env var:", envTestStruct)
+	}
+}
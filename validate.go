@@ -0,0 +1,168 @@
+package structflag
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate walks the same fields StructToFlags would register on v (a struct or a pointer to
+// one) and enforces the constraints declared in each field's validate tag: "required"
+// (non-zero), "min=N"/"max=N" for numeric and time.Duration fields, "oneof=a b c" for strings,
+// and "regexp=pattern" for strings. Constraints are comma-separated within the tag, e.g.
+// `validate:"required,min=1,max=65535"`. Nested and embedded struct fields are validated
+// recursively. It returns an error aggregating every failing field's flag name and rule, or
+// nil if every constraint is satisfied.
+//
+// ignoredFields excludes fields from validation the same way StructToFlags excludes them from
+// registration, matched against each field's full dash-joined path (e.g. "db-port" for a Port field
+// nested under DB), so ignoring one field never silently skips an unrelated, same-named field
+// elsewhere in the struct. Pass the same ignoredFields given to StructToFlags so a field with
+// no registered flag never fails a validate tag it has no way to satisfy.
+func Validate(v interface{}, ignoredFields ...string) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("structflag: Validate requires a struct or a pointer to one, got %T", v)
+	}
+	var errs []string
+	validateValue("", value, newStringSet(ignoredFields...), &errs)
+	if len(errs) > 0 {
+		return fmt.Errorf("structflag: validation failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// MustParse parses args with the flag package's default FlagSet (the same one StructToFlags
+// registers against) and then validates v with Validate, panicking with an aggregated error if
+// validation fails. A parse error is handled by flag.CommandLine's own error handling, which
+// exits the program by default; MustParse panics on a parse error only when flag.CommandLine
+// has been replaced by a FlagSet that returns its errors. ignoredFields is forwarded to
+// Validate as is.
+func MustParse(v interface{}, args []string, ignoredFields ...string) {
+	if err := flag.CommandLine.Parse(args); err != nil {
+		log.Panicln("structflag: failed to parse flags:", err)
+	}
+	if err := Validate(v, ignoredFields...); err != nil {
+		log.Panicln(err)
+	}
+}
+
+func validateValue(prefix string, value reflect.Value, ignored stringSet, errs *[]string) {
+	typ := value.Type()
+	for i := 0; i < value.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _, _, hasTag := resolveFieldName(field, nil)
+		fullName := joinSegment(prefix, name)
+		if name == "-" || ignored.Contains(fullName) {
+			continue
+		}
+		fieldValue := value.Field(i)
+		if _, ok := parserRegistry[fieldValue.Type()]; !ok && isNestedStruct(fieldValue) && fieldValue.Type() != durationType {
+			segment := name
+			if field.Anonymous && !hasTag {
+				segment = ""
+			}
+			validateValue(joinSegment(prefix, segment), fieldValue, ignored, errs)
+			continue
+		}
+		rules := field.Tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+		for _, rule := range strings.Split(rules, ",") {
+			if err := checkRule(fullName, fieldValue, rule); err != nil {
+				*errs = append(*errs, err.Error())
+			}
+		}
+	}
+}
+
+func checkRule(name string, fieldValue reflect.Value, rule string) error {
+	rule = strings.TrimSpace(rule)
+	if rule == "" {
+		return nil
+	}
+	key, arg, _ := strings.Cut(rule, "=")
+	switch key {
+	case "required":
+		if fieldValue.IsZero() {
+			return fmt.Errorf("%s is required", name)
+		}
+	case "min":
+		return checkBound(name, fieldValue, arg, false)
+	case "max":
+		return checkBound(name, fieldValue, arg, true)
+	case "oneof":
+		if fieldValue.Kind() != reflect.String {
+			return fmt.Errorf("%s: oneof only applies to string fields", name)
+		}
+		s := fieldValue.String()
+		for _, option := range strings.Fields(arg) {
+			if s == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of %q, got %q", name, arg, s)
+	case "regexp":
+		if fieldValue.Kind() != reflect.String {
+			return fmt.Errorf("%s: regexp only applies to string fields", name)
+		}
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return fmt.Errorf("%s has invalid regexp rule %q: %w", name, arg, err)
+		}
+		if !re.MatchString(fieldValue.String()) {
+			return fmt.Errorf("%s does not match pattern %q", name, arg)
+		}
+	default:
+		return fmt.Errorf("%s has unknown validate rule %q", name, key)
+	}
+	return nil
+}
+
+func checkBound(name string, fieldValue reflect.Value, arg string, isMax bool) error {
+	if fieldValue.Type() == durationType {
+		bound, err := time.ParseDuration(arg)
+		if err != nil {
+			return fmt.Errorf("%s has invalid bound %q: %w", name, arg, err)
+		}
+		v := time.Duration(fieldValue.Int())
+		if isMax && v > bound {
+			return fmt.Errorf("%s must be at most %s, got %s", name, bound, v)
+		}
+		if !isMax && v < bound {
+			return fmt.Errorf("%s must be at least %s, got %s", name, bound, v)
+		}
+		return nil
+	}
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return fmt.Errorf("%s has invalid bound %q: %w", name, arg, err)
+	}
+	var v float64
+	switch fieldValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v = float64(fieldValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v = float64(fieldValue.Uint())
+	case reflect.Float32, reflect.Float64:
+		v = fieldValue.Float()
+	default:
+		return fmt.Errorf("%s: min/max only apply to numeric or duration fields", name)
+	}
+	if isMax && v > bound {
+		return fmt.Errorf("%s must be at most %v, got %v", name, bound, v)
+	}
+	if !isMax && v < bound {
+		return fmt.Errorf("%s must be at least %v, got %v", name, bound, v)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package structflag
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ValidateTestStruct struct {
+	Name    string        `validate:"required,oneof=foo bar"`
+	Port    int           `validate:"min=1,max=65535"`
+	Timeout time.Duration `validate:"min=1s,max=1m"`
+	Code    string        `validate:"regexp=^[A-Z]{3}$"`
+}
+
+func TestValidateOK(t *testing.T) {
+	v := ValidateTestStruct{Name: "foo", Port: 8080, Timeout: 5 * time.Second, Code: "ABC"}
+	if err := Validate(&v); err != nil {
+		t.Error("Unexpected validation error:", err)
+	}
+}
+
+func TestValidateFailures(t *testing.T) {
+	v := ValidateTestStruct{Name: "", Port: 70000, Timeout: 2 * time.Minute, Code: "abc"}
+	err := Validate(&v)
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	for _, want := range []string{"required", "at most", "at most", "pattern"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %q, got: %v", want, err)
+		}
+	}
+}
+
+func TestValidateIgnoredFields(t *testing.T) {
+	v := ValidateTestStruct{Port: 8080, Timeout: 5 * time.Second, Code: "ABC"}
+	if err := Validate(&v, "Name"); err != nil {
+		t.Error("Expected ignored field to skip its validate tag, got:", err)
+	}
+}
+
+type ValidateCollisionStruct struct {
+	Name string `validate:"required"`
+	DB   struct {
+		Name string `validate:"required"`
+	} `flag:"db"`
+}
+
+func TestValidateIgnoredFieldsFullPath(t *testing.T) {
+	v := ValidateCollisionStruct{Name: "top"}
+	err := Validate(&v, "Name")
+	if err == nil || !strings.Contains(err.Error(), "db-Name") {
+		t.Error("Ignoring top-level \"Name\" should not skip validation of the nested db.Name field, got:", err)
+	}
+}
+
+type NestedValidateStruct struct {
+	DB struct {
+		Host string `flag:"host" validate:"required"`
+	} `flag:"db"`
+}
+
+func TestValidateNested(t *testing.T) {
+	var v NestedValidateStruct
+	err := Validate(&v)
+	if err == nil || !strings.Contains(err.Error(), "db-host") {
+		t.Error("Expected nested field name in validation error, got:", err)
+	}
+}
+
+type MustParseTestStruct struct {
+	Name string `flag:"mustparse-name" validate:"required"`
+}
+
+func TestMustParsePanicsOnValidationFailure(t *testing.T) {
+	var v MustParseTestStruct
+	StructToFlags("", &v)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected MustParse to panic on a failing required field")
+		}
+		if !strings.Contains(fmt.Sprint(r), "mustparse-name") {
+			t.Errorf("Expected panic message to mention mustparse-name, got: %v", r)
+		}
+	}()
+	MustParse(&v, nil)
+}